@@ -0,0 +1,384 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/internal/translation"
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultExcludeCategories maps a default_excludes category name to the
+// metric name/pattern prefixes it owns. A metric filter entry belongs to a
+// category if its literal name, or the unescaped/unanchored text of its
+// regex, contains one of the category's prefixes anywhere (see
+// isDisabledCategory) -- not just as a leading prefix, since alternations
+// like the kubelet `(?i:(container)|(k8s\.node)|...)` entry carry the
+// category prefix mid-pattern. This lets callers disable a whole category
+// of DefaultExcludeMetricsYaml (e.g. "cpu") without having to enumerate
+// every metric name in it.
+var defaultExcludeCategories = map[string][]string{
+	"cpu":        {"cpu.", "system.cpu."},
+	"disk_io":    {"disk_ops.", "system.disk."},
+	"paging":     {"vmpage_io.", "system.paging."},
+	"memory":     {"system.memory."},
+	"filesystem": {"system.filesystem."},
+	"network_io": {"system.network."},
+	"processes":  {"system.processes."},
+	"k8s":        {"k8s.", "container."},
+}
+
+// excludeMetricsYaml is the document shape of DefaultExcludeMetricsYaml and
+// any user-provided `exclude_metrics` override.
+type excludeMetricsYaml struct {
+	ExcludeMetrics []excludeMetricsItem `yaml:"exclude_metrics"`
+}
+
+// excludeMetricsItem is a single YAML list entry: either a batch of
+// metric name globs/regexes/negations, or a single metric name qualified
+// by dimension values.
+type excludeMetricsItem struct {
+	MetricName  string              `yaml:"metric_name"`
+	MetricNames []string            `yaml:"metric_names"`
+	Dimensions  map[string][]string `yaml:"dimensions"`
+}
+
+// dimensionRule excludes data points of MetricName only when every
+// configured dimension key has a value in its allow-list (or the allow-list
+// contains the wildcard "*").
+type dimensionRule struct {
+	metricName string
+	dims       map[string][]string
+}
+
+// regexRule is a compiled regex exclusion, plus how often it has matched so
+// far so frequently-hit rules can be moved toward the front of the slice
+// Match evaluates.
+type regexRule struct {
+	raw     string
+	pattern *regexp.Regexp
+	hits    uint64
+}
+
+// MetricFilterSet is a compiled, mergeable metric exclusion matcher parsed
+// from the `exclude_metrics` YAML format used throughout this package. It
+// keeps literal names in a set for O(1) lookup and evaluates regexes only
+// after a literal/dimension match fails, ordered so that the
+// most-frequently-matching regex is tried first.
+type MetricFilterSet struct {
+	literals   map[string]struct{}
+	negations  map[string]struct{}
+	regexes    []*regexRule
+	dimensions []dimensionRule
+
+	mu      sync.Mutex
+	dropped map[string]int64
+}
+
+// NewMetricFilterSet parses excludeMetricsYaml (the `exclude_metrics`
+// document format used by DefaultExcludeMetricsYaml) into a compiled
+// MetricFilterSet.
+func NewMetricFilterSet(yamlStr string) (*MetricFilterSet, error) {
+	var doc excludeMetricsYaml
+	if err := yaml.Unmarshal([]byte(yamlStr), &doc); err != nil {
+		return nil, fmt.Errorf("parse exclude_metrics: %w", err)
+	}
+
+	fs := newEmptyMetricFilterSet()
+	for _, item := range doc.ExcludeMetrics {
+		if err := fs.addItem(item); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+func newEmptyMetricFilterSet() *MetricFilterSet {
+	return &MetricFilterSet{
+		literals:  make(map[string]struct{}),
+		negations: make(map[string]struct{}),
+		dropped:   make(map[string]int64),
+	}
+}
+
+func (fs *MetricFilterSet) addItem(item excludeMetricsItem) error {
+	if len(item.Dimensions) > 0 {
+		if item.MetricName == "" {
+			return fmt.Errorf("exclude_metrics entry has dimensions but no metric_name")
+		}
+		fs.dimensions = append(fs.dimensions, dimensionRule{metricName: item.MetricName, dims: item.Dimensions})
+		return nil
+	}
+
+	if item.MetricName != "" {
+		return fs.addPattern(item.MetricName)
+	}
+
+	for _, name := range item.MetricNames {
+		if err := fs.addPattern(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs *MetricFilterSet) addPattern(pattern string) error {
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		fs.negations[strings.TrimPrefix(pattern, "!")] = struct{}{}
+	case strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") && len(pattern) > 1:
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return fmt.Errorf("compile exclude_metrics regex %q: %w", pattern, err)
+		}
+		fs.regexes = append(fs.regexes, &regexRule{raw: pattern, pattern: re})
+	default:
+		fs.literals[pattern] = struct{}{}
+	}
+	return nil
+}
+
+// Match reports whether a data point named name, with the given
+// dimensions, should be dropped.
+func (fs *MetricFilterSet) Match(name string, dims map[string]string) bool {
+	if _, negated := fs.negations[name]; negated {
+		return false
+	}
+
+	for _, rule := range fs.dimensions {
+		if rule.metricName == name && dimensionsMatch(rule.dims, dims) {
+			fs.recordDrop("dimension:" + name)
+			return true
+		}
+	}
+
+	if _, ok := fs.literals[name]; ok {
+		fs.recordDrop("literal:" + name)
+		return true
+	}
+
+	if raw, ok := fs.matchRegex(name); ok {
+		fs.recordDrop("regex:" + raw)
+		return true
+	}
+
+	return false
+}
+
+// matchRegex evaluates fs.regexes in order, promoting a matching rule ahead
+// of its neighbor once it becomes the more frequently hit of the two. Match
+// is called concurrently (once per data point, from multiple exporter
+// goroutines), so the scan and the hit-count/reorder bookkeeping share fs.mu
+// rather than mutating regexRule.hits or fs.regexes unguarded.
+func (fs *MetricFilterSet) matchRegex(name string) (string, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for i, rule := range fs.regexes {
+		if !rule.pattern.MatchString(name) {
+			continue
+		}
+		rule.hits++
+		if i > 0 && rule.hits > fs.regexes[i-1].hits {
+			fs.regexes[i-1], fs.regexes[i] = fs.regexes[i], fs.regexes[i-1]
+		}
+		return rule.raw, true
+	}
+	return "", false
+}
+
+func dimensionsMatch(allow map[string][]string, actual map[string]string) bool {
+	for key, values := range allow {
+		v, ok := actual[key]
+		if !ok {
+			return false
+		}
+		if !containsOrWildcard(values, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsOrWildcard(values []string, v string) bool {
+	for _, allowed := range values {
+		if allowed == "*" || allowed == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (fs *MetricFilterSet) recordDrop(rule string) {
+	fs.mu.Lock()
+	fs.dropped[rule]++
+	fs.mu.Unlock()
+}
+
+// DroppedPoints returns the number of points dropped per matching rule
+// since the MetricFilterSet was created, keyed the same way Match's
+// internal rule identifiers are constructed (e.g. "literal:cpu.idle",
+// "regex:/^k8s\\.node\\..+$/"). RegisterTelemetry is the intended way for
+// an exporter to surface these counts as a collector metric; DroppedPoints
+// itself just snapshots the current tally.
+func (fs *MetricFilterSet) DroppedPoints() map[string]int64 {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	out := make(map[string]int64, len(fs.dropped))
+	for k, v := range fs.dropped {
+		out[k] = v
+	}
+	return out
+}
+
+// droppedPointsInstrumentName is the collector-internal metric RegisterTelemetry
+// publishes, following the otelcol_<component>_<signal> naming the core
+// collector uses for its own obsreport metrics.
+const droppedPointsInstrumentName = "otelcol_exporter_signalfx_datapoints_dropped"
+
+// RegisterTelemetry registers an asynchronous counter on meter that reports
+// DroppedPoints on each collection, broken out by the "rule" attribute, so
+// operators can see which exclude_metrics rule is responsible for dropped
+// data points. The exporter is expected to call this once at startup with a
+// Meter obtained from its own TelemetrySettings; MetricFilterSet has no
+// opinion on how that meter was constructed.
+func (fs *MetricFilterSet) RegisterTelemetry(meter metric.Meter) error {
+	counter, err := meter.Int64ObservableCounter(
+		droppedPointsInstrumentName,
+		metric.WithDescription("Number of datapoints dropped by the signalfx exporter, per exclude_metrics rule"),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return fmt.Errorf("register %s: %w", droppedPointsInstrumentName, err)
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for rule, count := range fs.DroppedPoints() {
+			o.ObserveInt64(counter, count, metric.WithAttributes(attribute.String("rule", rule)))
+		}
+		return nil
+	}, counter)
+	if err != nil {
+		return fmt.Errorf("register %s callback: %w", droppedPointsInstrumentName, err)
+	}
+	return nil
+}
+
+// Merge folds other's literals, negations, regexes, and dimension rules
+// into fs, so a user can override individual default entries (e.g.
+// re-include "cpu.system" by merging in a filter set containing only
+// "!cpu.system") without re-declaring DefaultExcludeMetricsYaml in full.
+func (fs *MetricFilterSet) Merge(other *MetricFilterSet) {
+	if other == nil {
+		return
+	}
+
+	for name := range other.literals {
+		fs.literals[name] = struct{}{}
+	}
+	for name := range other.negations {
+		fs.negations[name] = struct{}{}
+	}
+
+	existing := make(map[string]struct{}, len(fs.regexes))
+	for _, r := range fs.regexes {
+		existing[r.raw] = struct{}{}
+	}
+	for _, r := range other.regexes {
+		if _, ok := existing[r.raw]; ok {
+			continue
+		}
+		fs.regexes = append(fs.regexes, &regexRule{raw: r.raw, pattern: r.pattern})
+	}
+
+	fs.dimensions = append(fs.dimensions, other.dimensions...)
+}
+
+// NewDefaultMetricFilterSet compiles DefaultExcludeMetricsYaml, skipping
+// any category named in disabledCategories (e.g. "cpu", "disk_io",
+// "paging", "memory", "filesystem", "network_io", "processes", "k8s"). An
+// unrecognized category name is an error, to catch config typos early.
+func NewDefaultMetricFilterSet(disabledCategories ...string) (*MetricFilterSet, error) {
+	disabled := make(map[string]struct{}, len(disabledCategories))
+	for _, c := range disabledCategories {
+		if _, ok := defaultExcludeCategories[c]; !ok {
+			return nil, fmt.Errorf("unknown default_excludes category %q", c)
+		}
+		disabled[c] = struct{}{}
+	}
+
+	var doc excludeMetricsYaml
+	if err := yaml.Unmarshal([]byte(DefaultExcludeMetricsYaml), &doc); err != nil {
+		return nil, fmt.Errorf("parse DefaultExcludeMetricsYaml: %w", err)
+	}
+
+	fs := newEmptyMetricFilterSet()
+	for _, item := range doc.ExcludeMetrics {
+		if len(item.Dimensions) > 0 {
+			if isDisabledCategory(item.MetricName, disabled) {
+				continue
+			}
+			if err := fs.addItem(item); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if item.MetricName != "" {
+			if isDisabledCategory(item.MetricName, disabled) {
+				continue
+			}
+			if err := fs.addPattern(item.MetricName); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, name := range item.MetricNames {
+			if isDisabledCategory(strings.TrimPrefix(name, "!"), disabled) {
+				continue
+			}
+			if err := fs.addPattern(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fs, nil
+}
+
+func isDisabledCategory(pattern string, disabled map[string]struct{}) bool {
+	if len(disabled) == 0 {
+		return false
+	}
+	// Regex entries carry their literal delimiters, anchors, and escaped
+	// dots (e.g. "/^k8s\.container\..+_limit$/"); strip all of that so the
+	// prefix check below runs against the same unescaped text a literal
+	// entry like "k8s.container.memory_limit" already uses.
+	trimmed := strings.TrimLeft(strings.Trim(pattern, "/"), "^")
+	trimmed = strings.ReplaceAll(trimmed, `\`, "")
+	for category := range disabled {
+		for _, prefix := range defaultExcludeCategories[category] {
+			if strings.Contains(trimmed, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
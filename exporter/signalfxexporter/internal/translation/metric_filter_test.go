@@ -0,0 +1,215 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetricFilterSet_LiteralAndRegexMatch(t *testing.T) {
+	fs, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_names:
+    - cpu.idle
+  - metric_name: /^disk\..+$/
+  - metric_name: '!disk.keep'
+`)
+	require.NoError(t, err)
+
+	assert.True(t, fs.Match("cpu.idle", nil))
+	assert.True(t, fs.Match("disk.read", nil))
+	assert.False(t, fs.Match("disk.keep", nil))
+	assert.False(t, fs.Match("cpu.system", nil))
+}
+
+func TestMetricFilterSet_DimensionRule(t *testing.T) {
+	fs, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_name: network.interface
+    dimensions:
+      interface: [lo, veth0]
+`)
+	require.NoError(t, err)
+
+	assert.True(t, fs.Match("network.interface", map[string]string{"interface": "lo"}))
+	assert.False(t, fs.Match("network.interface", map[string]string{"interface": "eth0"}))
+}
+
+func TestMetricFilterSet_Merge(t *testing.T) {
+	base, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_names:
+    - cpu.idle
+    - cpu.system
+`)
+	require.NoError(t, err)
+
+	override, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_name: '!cpu.system'
+`)
+	require.NoError(t, err)
+
+	base.Merge(override)
+
+	assert.True(t, base.Match("cpu.idle", nil))
+	assert.False(t, base.Match("cpu.system", nil))
+}
+
+func TestMetricFilterSet_MergeNegationOverridesDimensionRule(t *testing.T) {
+	base, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_name: cpu.idle
+    dimensions:
+      cpu: ["*"]
+`)
+	require.NoError(t, err)
+
+	// Sanity check: without the override, the dimension rule drops it.
+	assert.True(t, base.Match("cpu.idle", map[string]string{"cpu": "0"}))
+
+	override, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_name: '!cpu.idle'
+`)
+	require.NoError(t, err)
+
+	base.Merge(override)
+
+	assert.False(t, base.Match("cpu.idle", map[string]string{"cpu": "0"}))
+}
+
+func TestMetricFilterSet_DroppedPoints(t *testing.T) {
+	fs, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_names:
+    - cpu.idle
+`)
+	require.NoError(t, err)
+
+	fs.Match("cpu.idle", nil)
+	fs.Match("cpu.idle", nil)
+
+	assert.Equal(t, int64(2), fs.DroppedPoints()["literal:cpu.idle"])
+}
+
+func TestMetricFilterSet_RegisterTelemetry(t *testing.T) {
+	fs, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_names:
+    - cpu.idle
+`)
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("signalfxexporter")
+	require.NoError(t, fs.RegisterTelemetry(meter))
+
+	fs.Match("cpu.idle", nil)
+	fs.Match("cpu.idle", nil)
+	fs.Match("cpu.system", nil) // not excluded, shouldn't be counted
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != droppedPointsInstrumentName {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			require.Len(t, sum.DataPoints, 1)
+			dp := sum.DataPoints[0]
+			rule, ok := dp.Attributes.Value("rule")
+			require.True(t, ok)
+			assert.Equal(t, "literal:cpu.idle", rule.AsString())
+			assert.Equal(t, int64(2), dp.Value)
+			found = true
+		}
+	}
+	assert.True(t, found, "expected %s to be reported", droppedPointsInstrumentName)
+}
+
+func TestMetricFilterSet_MatchConcurrent(t *testing.T) {
+	fs, err := NewMetricFilterSet(`
+exclude_metrics:
+  - metric_name: /^k8s\..+$/
+  - metric_name: /^container\..+$/
+  - metric_name: /^system\..+$/
+`)
+	require.NoError(t, err)
+
+	names := []string{"k8s.pod.cpu", "container.memory", "system.disk", "unmatched.metric"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fs.Match(names[i%len(names)], nil)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestNewDefaultMetricFilterSet_DisableK8sCategoryKeepsNegations(t *testing.T) {
+	fs, err := NewDefaultMetricFilterSet("k8s")
+	require.NoError(t, err)
+
+	// With the category enabled, the default k8s rules drop these names
+	// except the ones a '!' negation carves back out (memory_limit,
+	// cpu_limit, condition_ready); with it disabled none of the k8s rules
+	// (including the regexes whose literal text is backslash-escaped)
+	// apply, so nothing in this category is dropped.
+	assert.False(t, fs.Match("k8s.container.memory_limit", nil))
+	assert.False(t, fs.Match("k8s.container.cpu_limit", nil))
+	assert.False(t, fs.Match("k8s.container.some_other_limit", nil))
+	assert.False(t, fs.Match("k8s.node.condition_ready", nil))
+	assert.False(t, fs.Match("k8s.node.condition_something_else", nil))
+	assert.False(t, fs.Match("k8s.cronjob.active_jobs", nil))
+
+	// The kubelet rules mix literal "container." text with a
+	// case-insensitive group containing k8s.node/k8s.pod
+	// (e.g. /^(?i:(container)|(k8s\.node)|(k8s\.pod))\.memory\.available$/);
+	// "k8s." alone is enough to classify them without dedicated
+	// "(?i:(container)" / "(?i:(k8s" fragments in defaultExcludeCategories.
+	assert.False(t, fs.Match("container.memory.available", nil))
+	assert.False(t, fs.Match("k8s.node.memory.available", nil))
+	assert.False(t, fs.Match("k8s.pod.filesystem.usage", nil))
+}
+
+func TestNewDefaultMetricFilterSet_K8sCategoryEnabledByDefault(t *testing.T) {
+	fs, err := NewDefaultMetricFilterSet()
+	require.NoError(t, err)
+
+	assert.True(t, fs.Match("k8s.cronjob.active_jobs", nil))
+	// Excluded by the broad regex, but carved back out by its negation.
+	assert.False(t, fs.Match("k8s.container.memory_limit", nil))
+	assert.True(t, fs.Match("k8s.container.some_other_limit", nil))
+}
+
+func TestNewDefaultMetricFilterSet_UnknownCategory(t *testing.T) {
+	_, err := NewDefaultMetricFilterSet("not-a-real-category")
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/multierr"
+)
+
+// CompareLogs compares expected and actual plog.Logs and returns an error
+// describing every mismatch found, path-qualified the same way as
+// CompareMetrics.
+func CompareLogs(expected, actual plog.Logs, opts ...Option) error {
+	o := applyOptions(opts)
+
+	expectedRLs := expected.ResourceLogs()
+	actualRLs := actual.ResourceLogs()
+	if o.SortSlices {
+		sortResourceLogs(expectedRLs)
+		sortResourceLogs(actualRLs)
+	}
+
+	if expectedRLs.Len() != actualRLs.Len() {
+		return fmt.Errorf("number of resourceLogs does not match expected: %d, actual: %d", expectedRLs.Len(), actualRLs.Len())
+	}
+
+	var errs error
+	for i := 0; i < expectedRLs.Len(); i++ {
+		errs = multierr.Append(errs, compareResourceLogs(fmt.Sprintf("resourceLogs[%d]", i), expectedRLs.At(i), actualRLs.At(i), o))
+	}
+	return errs
+}
+
+func compareResourceLogs(path string, expected, actual plog.ResourceLogs, o Options) error {
+	var errs error
+	errs = multierr.Append(errs, compareAttributes(path+".resource.attributes", expected.Resource().Attributes(), actual.Resource().Attributes(), o))
+
+	expectedSLs := expected.ScopeLogs()
+	actualSLs := actual.ScopeLogs()
+	if expectedSLs.Len() != actualSLs.Len() {
+		return multierr.Append(errs, fmt.Errorf("%s: number of scopeLogs does not match expected: %d, actual: %d", path, expectedSLs.Len(), actualSLs.Len()))
+	}
+
+	for i := 0; i < expectedSLs.Len(); i++ {
+		errs = multierr.Append(errs, compareScopeLogs(fmt.Sprintf("%s.scopeLogs[%d]", path, i), expectedSLs.At(i), actualSLs.At(i), o))
+	}
+	return errs
+}
+
+func compareScopeLogs(path string, expected, actual plog.ScopeLogs, o Options) error {
+	expectedLRs := expected.LogRecords()
+	actualLRs := actual.LogRecords()
+	if o.SortSlices {
+		sortLogRecords(expectedLRs)
+		sortLogRecords(actualLRs)
+	}
+
+	if expectedLRs.Len() != actualLRs.Len() {
+		return fmt.Errorf("%s: number of logRecords does not match expected: %d, actual: %d", path, expectedLRs.Len(), actualLRs.Len())
+	}
+
+	var errs error
+	for i := 0; i < expectedLRs.Len(); i++ {
+		errs = multierr.Append(errs, compareLogRecord(fmt.Sprintf("%s.logRecords[%d]", path, i), expectedLRs.At(i), actualLRs.At(i), o))
+	}
+	return errs
+}
+
+func compareLogRecord(path string, expected, actual plog.LogRecord, o Options) error {
+	var errs error
+	errs = multierr.Append(errs, compareAttributes(path+".attributes", expected.Attributes(), actual.Attributes(), o))
+
+	if expected.Body().AsString() != actual.Body().AsString() {
+		errs = multierr.Append(errs, fmt.Errorf("%s.body: expected %q, got %q", path, expected.Body().AsString(), actual.Body().AsString()))
+	}
+	if expected.SeverityText() != actual.SeverityText() {
+		errs = multierr.Append(errs, fmt.Errorf("%s.severityText: expected %q, got %q", path, expected.SeverityText(), actual.SeverityText()))
+	}
+	if !o.IgnoreTimestamps && expected.Timestamp() != actual.Timestamp() {
+		errs = multierr.Append(errs, fmt.Errorf("%s.timestamp: expected %v, got %v", path, expected.Timestamp(), actual.Timestamp()))
+	}
+	return errs
+}
+
+func sortLogRecords(lrs plog.LogRecordSlice) {
+	lrs.Sort(func(a, b plog.LogRecord) bool {
+		return attributesKey(a.Attributes()) < attributesKey(b.Attributes())
+	})
+}
+
+func sortResourceLogs(rls plog.ResourceLogsSlice) {
+	rls.Sort(func(a, b plog.ResourceLogs) bool {
+		return attributesKey(a.Resource().Attributes()) < attributesKey(b.Resource().Attributes())
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newTestLogRecord(rsAttr, body string) plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("resource", rsAttr)
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Body().SetStr(body)
+	return ld
+}
+
+func TestCompareLogs_BodyDiffers(t *testing.T) {
+	expected := newTestLogRecord("r1", "hello")
+	actual := newTestLogRecord("r1", "goodbye")
+
+	err := CompareLogs(expected, actual)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "body")
+}
+
+func TestCompareLogs_Match(t *testing.T) {
+	expected := newTestLogRecord("r1", "hello")
+	actual := newTestLogRecord("r1", "hello")
+
+	assert.NoError(t, CompareLogs(expected, actual))
+}
+
+func TestCompareLogs_ResourceOrderSortSlices(t *testing.T) {
+	expected := plog.NewLogs()
+	rl1 := expected.ResourceLogs().AppendEmpty()
+	rl1.Resource().Attributes().PutStr("resource", "r1")
+	rl1.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("first")
+	rl2 := expected.ResourceLogs().AppendEmpty()
+	rl2.Resource().Attributes().PutStr("resource", "r2")
+	rl2.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("second")
+
+	actual := plog.NewLogs()
+	arl1 := actual.ResourceLogs().AppendEmpty()
+	arl1.Resource().Attributes().PutStr("resource", "r2")
+	arl1.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("second")
+	arl2 := actual.ResourceLogs().AppendEmpty()
+	arl2.Resource().Attributes().PutStr("resource", "r1")
+	arl2.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("first")
+
+	assert.Error(t, CompareLogs(expected, actual))
+	assert.NoError(t, CompareLogs(expected, actual, SortSlices()))
+}
@@ -0,0 +1,374 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+)
+
+// CompareMetrics compares expected and actual pmetric.Metrics and returns an
+// error describing every mismatch found, each qualified with the path to
+// the offending field (e.g.
+// "resourceMetrics[0].scopeMetrics[0].metrics[3].sum.dataPoints[1].value:
+// expected 42, got 43"). A nil return means expected and actual are
+// equivalent under the given Options.
+func CompareMetrics(expected, actual pmetric.Metrics, opts ...Option) error {
+	o := applyOptions(opts)
+
+	expectedRMs := expected.ResourceMetrics()
+	actualRMs := actual.ResourceMetrics()
+	if o.SortSlices {
+		sortResourceMetrics(expectedRMs)
+		sortResourceMetrics(actualRMs)
+	}
+
+	if expectedRMs.Len() != actualRMs.Len() {
+		return fmt.Errorf("number of resourceMetrics does not match expected: %d, actual: %d", expectedRMs.Len(), actualRMs.Len())
+	}
+
+	var errs error
+	for i := 0; i < expectedRMs.Len(); i++ {
+		errs = multierr.Append(errs, compareResourceMetrics(fmt.Sprintf("resourceMetrics[%d]", i), expectedRMs.At(i), actualRMs.At(i), o))
+	}
+	return errs
+}
+
+func compareResourceMetrics(path string, expected, actual pmetric.ResourceMetrics, o Options) error {
+	var errs error
+	errs = multierr.Append(errs, compareAttributes(path+".resource.attributes", expected.Resource().Attributes(), actual.Resource().Attributes(), o))
+
+	expectedSMs := expected.ScopeMetrics()
+	actualSMs := actual.ScopeMetrics()
+	if expectedSMs.Len() != actualSMs.Len() {
+		return multierr.Append(errs, fmt.Errorf("%s: number of scopeMetrics does not match expected: %d, actual: %d", path, expectedSMs.Len(), actualSMs.Len()))
+	}
+
+	for i := 0; i < expectedSMs.Len(); i++ {
+		errs = multierr.Append(errs, compareScopeMetrics(fmt.Sprintf("%s.scopeMetrics[%d]", path, i), expectedSMs.At(i), actualSMs.At(i), o))
+	}
+	return errs
+}
+
+func compareScopeMetrics(path string, expected, actual pmetric.ScopeMetrics, o Options) error {
+	expectedMs := expected.Metrics()
+	actualMs := actual.Metrics()
+	if o.SortSlices {
+		sortMetrics(expectedMs)
+		sortMetrics(actualMs)
+	}
+
+	if expectedMs.Len() != actualMs.Len() {
+		return fmt.Errorf("%s: number of metrics does not match expected: %d, actual: %d", path, expectedMs.Len(), actualMs.Len())
+	}
+
+	var errs error
+	for i := 0; i < expectedMs.Len(); i++ {
+		errs = multierr.Append(errs, compareMetric(fmt.Sprintf("%s.metrics[%d]", path, i), expectedMs.At(i), actualMs.At(i), o))
+	}
+	return errs
+}
+
+func compareMetric(path string, expected, actual pmetric.Metric, o Options) error {
+	if expected.Name() != actual.Name() {
+		return fmt.Errorf("%s.name: expected %q, got %q", path, expected.Name(), actual.Name())
+	}
+	if expected.Type() != actual.Type() {
+		return fmt.Errorf("%s.type: expected %v, got %v", path, expected.Type(), actual.Type())
+	}
+
+	switch expected.Type() {
+	case pmetric.MetricTypeGauge:
+		return compareNumberDataPoints(path+".gauge.dataPoints", expected.Gauge().DataPoints(), actual.Gauge().DataPoints(), o)
+	case pmetric.MetricTypeSum:
+		return compareNumberDataPoints(path+".sum.dataPoints", expected.Sum().DataPoints(), actual.Sum().DataPoints(), o)
+	case pmetric.MetricTypeHistogram:
+		return compareHistogramDataPoints(path+".histogram.dataPoints", expected.Histogram().DataPoints(), actual.Histogram().DataPoints(), o)
+	case pmetric.MetricTypeExponentialHistogram:
+		return compareExponentialHistogramDataPoints(path+".exponentialHistogram.dataPoints", expected.ExponentialHistogram().DataPoints(), actual.ExponentialHistogram().DataPoints(), o)
+	case pmetric.MetricTypeSummary:
+		return compareSummaryDataPoints(path+".summary.dataPoints", expected.Summary().DataPoints(), actual.Summary().DataPoints(), o)
+	default:
+		return fmt.Errorf("%s: unsupported metric type %v", path, expected.Type())
+	}
+}
+
+func compareNumberDataPoints(path string, expected, actual pmetric.NumberDataPointSlice, o Options) error {
+	if o.SortSlices {
+		sortNumberDataPoints(expected)
+		sortNumberDataPoints(actual)
+	}
+	if expected.Len() != actual.Len() {
+		return fmt.Errorf("%s: number of data points does not match expected: %d, actual: %d", path, expected.Len(), actual.Len())
+	}
+
+	var errs error
+	for i := 0; i < expected.Len(); i++ {
+		dpPath := fmt.Sprintf("%s[%d]", path, i)
+		exp, act := expected.At(i), actual.At(i)
+
+		errs = multierr.Append(errs, compareAttributes(dpPath+".attributes", exp.Attributes(), act.Attributes(), o))
+
+		if !o.IgnoreTimestamps && exp.Timestamp() != act.Timestamp() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.timestamp: expected %v, got %v", dpPath, exp.Timestamp(), act.Timestamp()))
+		}
+
+		expVal, actVal := numberDataPointValue(exp), numberDataPointValue(act)
+		if !floatsEqual(expVal, actVal, o.FloatComparisonDelta) {
+			errs = multierr.Append(errs, fmt.Errorf("%s.value: expected %v, got %v", dpPath, expVal, actVal))
+		}
+	}
+	return errs
+}
+
+func compareHistogramDataPoints(path string, expected, actual pmetric.HistogramDataPointSlice, o Options) error {
+	if o.SortSlices {
+		sortHistogramDataPoints(expected)
+		sortHistogramDataPoints(actual)
+	}
+	if expected.Len() != actual.Len() {
+		return fmt.Errorf("%s: number of data points does not match expected: %d, actual: %d", path, expected.Len(), actual.Len())
+	}
+
+	var errs error
+	for i := 0; i < expected.Len(); i++ {
+		dpPath := fmt.Sprintf("%s[%d]", path, i)
+		exp, act := expected.At(i), actual.At(i)
+
+		errs = multierr.Append(errs, compareAttributes(dpPath+".attributes", exp.Attributes(), act.Attributes(), o))
+
+		if exp.Count() != act.Count() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.count: expected %d, got %d", dpPath, exp.Count(), act.Count()))
+		}
+		if !floatsEqual(exp.Sum(), act.Sum(), o.FloatComparisonDelta) {
+			errs = multierr.Append(errs, fmt.Errorf("%s.sum: expected %v, got %v", dpPath, exp.Sum(), act.Sum()))
+		}
+		errs = multierr.Append(errs, compareUint64Slices(dpPath+".bucketCounts", exp.BucketCounts().AsRaw(), act.BucketCounts().AsRaw()))
+		errs = multierr.Append(errs, compareFloat64Slices(dpPath+".explicitBounds", exp.ExplicitBounds().AsRaw(), act.ExplicitBounds().AsRaw(), o.FloatComparisonDelta))
+	}
+	return errs
+}
+
+func compareExponentialHistogramDataPoints(path string, expected, actual pmetric.ExponentialHistogramDataPointSlice, o Options) error {
+	if o.SortSlices {
+		sortExponentialHistogramDataPoints(expected)
+		sortExponentialHistogramDataPoints(actual)
+	}
+	if expected.Len() != actual.Len() {
+		return fmt.Errorf("%s: number of data points does not match expected: %d, actual: %d", path, expected.Len(), actual.Len())
+	}
+
+	var errs error
+	for i := 0; i < expected.Len(); i++ {
+		dpPath := fmt.Sprintf("%s[%d]", path, i)
+		exp, act := expected.At(i), actual.At(i)
+
+		errs = multierr.Append(errs, compareAttributes(dpPath+".attributes", exp.Attributes(), act.Attributes(), o))
+
+		if exp.Count() != act.Count() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.count: expected %d, got %d", dpPath, exp.Count(), act.Count()))
+		}
+		if !floatsEqual(exp.Sum(), act.Sum(), o.FloatComparisonDelta) {
+			errs = multierr.Append(errs, fmt.Errorf("%s.sum: expected %v, got %v", dpPath, exp.Sum(), act.Sum()))
+		}
+		if exp.Scale() != act.Scale() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.scale: expected %d, got %d", dpPath, exp.Scale(), act.Scale()))
+		}
+		if exp.ZeroCount() != act.ZeroCount() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.zeroCount: expected %d, got %d", dpPath, exp.ZeroCount(), act.ZeroCount()))
+		}
+		errs = multierr.Append(errs, compareExponentialHistogramBuckets(dpPath+".positive", exp.Positive(), act.Positive()))
+		errs = multierr.Append(errs, compareExponentialHistogramBuckets(dpPath+".negative", exp.Negative(), act.Negative()))
+	}
+	return errs
+}
+
+func compareExponentialHistogramBuckets(path string, expected, actual pmetric.ExponentialHistogramDataPointBuckets) error {
+	var errs error
+	if expected.Offset() != actual.Offset() {
+		errs = multierr.Append(errs, fmt.Errorf("%s.offset: expected %d, got %d", path, expected.Offset(), actual.Offset()))
+	}
+	errs = multierr.Append(errs, compareUint64Slices(path+".bucketCounts", expected.BucketCounts().AsRaw(), actual.BucketCounts().AsRaw()))
+	return errs
+}
+
+func compareSummaryDataPoints(path string, expected, actual pmetric.SummaryDataPointSlice, o Options) error {
+	if o.SortSlices {
+		sortSummaryDataPoints(expected)
+		sortSummaryDataPoints(actual)
+	}
+	if expected.Len() != actual.Len() {
+		return fmt.Errorf("%s: number of data points does not match expected: %d, actual: %d", path, expected.Len(), actual.Len())
+	}
+
+	var errs error
+	for i := 0; i < expected.Len(); i++ {
+		dpPath := fmt.Sprintf("%s[%d]", path, i)
+		exp, act := expected.At(i), actual.At(i)
+
+		errs = multierr.Append(errs, compareAttributes(dpPath+".attributes", exp.Attributes(), act.Attributes(), o))
+
+		if exp.Count() != act.Count() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.count: expected %d, got %d", dpPath, exp.Count(), act.Count()))
+		}
+		if !floatsEqual(exp.Sum(), act.Sum(), o.FloatComparisonDelta) {
+			errs = multierr.Append(errs, fmt.Errorf("%s.sum: expected %v, got %v", dpPath, exp.Sum(), act.Sum()))
+		}
+
+		expQ, actQ := exp.QuantileValues(), act.QuantileValues()
+		if expQ.Len() != actQ.Len() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.quantileValues: number of quantiles does not match expected: %d, actual: %d", dpPath, expQ.Len(), actQ.Len()))
+			continue
+		}
+		for j := 0; j < expQ.Len(); j++ {
+			qPath := fmt.Sprintf("%s.quantileValues[%d]", dpPath, j)
+			eq, aq := expQ.At(j), actQ.At(j)
+			if eq.Quantile() != aq.Quantile() {
+				errs = multierr.Append(errs, fmt.Errorf("%s.quantile: expected %v, got %v", qPath, eq.Quantile(), aq.Quantile()))
+			}
+			if !floatsEqual(eq.Value(), aq.Value(), o.FloatComparisonDelta) {
+				errs = multierr.Append(errs, fmt.Errorf("%s.value: expected %v, got %v", qPath, eq.Value(), aq.Value()))
+			}
+		}
+	}
+	return errs
+}
+
+func compareUint64Slices(path string, expected, actual []uint64) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("%s: length does not match expected: %d, actual: %d", path, len(expected), len(actual))
+	}
+	var errs error
+	for i := range expected {
+		if expected[i] != actual[i] {
+			errs = multierr.Append(errs, fmt.Errorf("%s[%d]: expected %d, got %d", path, i, expected[i], actual[i]))
+		}
+	}
+	return errs
+}
+
+func compareFloat64Slices(path string, expected, actual []float64, delta float64) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("%s: length does not match expected: %d, actual: %d", path, len(expected), len(actual))
+	}
+	var errs error
+	for i := range expected {
+		if !floatsEqual(expected[i], actual[i], delta) {
+			errs = multierr.Append(errs, fmt.Errorf("%s[%d]: expected %v, got %v", path, i, expected[i], actual[i]))
+		}
+	}
+	return errs
+}
+
+func numberDataPointValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+func floatsEqual(expected, actual, delta float64) bool {
+	if delta == 0 {
+		return expected == actual
+	}
+	return math.Abs(expected-actual) <= delta
+}
+
+func compareAttributes(path string, expected, actual pcommon.Map, o Options) error {
+	var errs error
+	expectedRaw := expected.AsRaw()
+	actualRaw := actual.AsRaw()
+
+	for k, v := range expectedRaw {
+		if o.maskedAttribute(k) {
+			continue
+		}
+		av, ok := actualRaw[k]
+		if !ok {
+			errs = multierr.Append(errs, fmt.Errorf("%s: missing expected attribute %q", path, k))
+			continue
+		}
+		if fmt.Sprintf("%v", v) != fmt.Sprintf("%v", av) {
+			errs = multierr.Append(errs, fmt.Errorf("%s[%q]: expected %v, got %v", path, k, v, av))
+		}
+	}
+	for k := range actualRaw {
+		if o.maskedAttribute(k) {
+			continue
+		}
+		if _, ok := expectedRaw[k]; !ok {
+			errs = multierr.Append(errs, fmt.Errorf("%s: unexpected attribute %q", path, k))
+		}
+	}
+	return errs
+}
+
+func sortResourceMetrics(rms pmetric.ResourceMetricsSlice) {
+	rms.Sort(func(a, b pmetric.ResourceMetrics) bool {
+		return attributesKey(a.Resource().Attributes()) < attributesKey(b.Resource().Attributes())
+	})
+}
+
+func sortMetrics(ms pmetric.MetricSlice) {
+	ms.Sort(func(a, b pmetric.Metric) bool {
+		return a.Name() < b.Name()
+	})
+}
+
+func sortNumberDataPoints(dps pmetric.NumberDataPointSlice) {
+	dps.Sort(func(a, b pmetric.NumberDataPoint) bool {
+		return attributesKey(a.Attributes()) < attributesKey(b.Attributes())
+	})
+}
+
+func sortHistogramDataPoints(dps pmetric.HistogramDataPointSlice) {
+	dps.Sort(func(a, b pmetric.HistogramDataPoint) bool {
+		return attributesKey(a.Attributes()) < attributesKey(b.Attributes())
+	})
+}
+
+func sortExponentialHistogramDataPoints(dps pmetric.ExponentialHistogramDataPointSlice) {
+	dps.Sort(func(a, b pmetric.ExponentialHistogramDataPoint) bool {
+		return attributesKey(a.Attributes()) < attributesKey(b.Attributes())
+	})
+}
+
+func sortSummaryDataPoints(dps pmetric.SummaryDataPointSlice) {
+	dps.Sort(func(a, b pmetric.SummaryDataPoint) bool {
+		return attributesKey(a.Attributes()) < attributesKey(b.Attributes())
+	})
+}
+
+// attributesKey produces a stable, comparable string for an attribute set
+// so slices can be sorted into a deterministic order before comparison.
+func attributesKey(m pcommon.Map) string {
+	raw := m.AsRaw()
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	key := ""
+	for _, k := range keys {
+		key += fmt.Sprintf("%s=%v;", k, raw[k])
+	}
+	return key
+}
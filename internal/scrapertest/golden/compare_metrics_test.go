@@ -0,0 +1,157 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func newTestMetrics(name string, typ pmetric.MetricType) (pmetric.Metrics, pmetric.Metric) {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	switch typ {
+	case pmetric.MetricTypeHistogram:
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeExponentialHistogram:
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeSummary:
+		m.SetEmptySummary()
+	}
+	return md, m
+}
+
+func TestCompareMetrics_HistogramBucketsDiffer(t *testing.T) {
+	expMd, expM := newTestMetrics("test.histogram", pmetric.MetricTypeHistogram)
+	dp := expM.Histogram().DataPoints().AppendEmpty()
+	dp.SetCount(3)
+	dp.SetSum(6)
+	dp.BucketCounts().FromRaw([]uint64{1, 1, 1})
+	dp.ExplicitBounds().FromRaw([]float64{1, 2})
+
+	actMd, actM := newTestMetrics("test.histogram", pmetric.MetricTypeHistogram)
+	actDp := actM.Histogram().DataPoints().AppendEmpty()
+	actDp.SetCount(3)
+	actDp.SetSum(6)
+	actDp.BucketCounts().FromRaw([]uint64{3, 0, 0})
+	actDp.ExplicitBounds().FromRaw([]float64{1, 2})
+
+	err := CompareMetrics(expMd, actMd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bucketCounts")
+}
+
+func TestCompareMetrics_HistogramBucketsMatch(t *testing.T) {
+	expMd, expM := newTestMetrics("test.histogram", pmetric.MetricTypeHistogram)
+	dp := expM.Histogram().DataPoints().AppendEmpty()
+	dp.SetCount(3)
+	dp.SetSum(6)
+	dp.BucketCounts().FromRaw([]uint64{1, 1, 1})
+	dp.ExplicitBounds().FromRaw([]float64{1, 2})
+
+	actMd, actM := newTestMetrics("test.histogram", pmetric.MetricTypeHistogram)
+	actDp := actM.Histogram().DataPoints().AppendEmpty()
+	actDp.SetCount(3)
+	actDp.SetSum(6)
+	actDp.BucketCounts().FromRaw([]uint64{1, 1, 1})
+	actDp.ExplicitBounds().FromRaw([]float64{1, 2})
+
+	assert.NoError(t, CompareMetrics(expMd, actMd))
+}
+
+func TestCompareMetrics_ExponentialHistogramDiffers(t *testing.T) {
+	expMd, expM := newTestMetrics("test.exp_histogram", pmetric.MetricTypeExponentialHistogram)
+	dp := expM.ExponentialHistogram().DataPoints().AppendEmpty()
+	dp.SetCount(2)
+	dp.SetScale(1)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 1})
+
+	actMd, actM := newTestMetrics("test.exp_histogram", pmetric.MetricTypeExponentialHistogram)
+	actDp := actM.ExponentialHistogram().DataPoints().AppendEmpty()
+	actDp.SetCount(2)
+	actDp.SetScale(1)
+	actDp.Positive().BucketCounts().FromRaw([]uint64{2, 0})
+
+	err := CompareMetrics(expMd, actMd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "positive.bucketCounts")
+}
+
+func TestCompareMetrics_SummaryDiffers(t *testing.T) {
+	expMd, expM := newTestMetrics("test.summary", pmetric.MetricTypeSummary)
+	dp := expM.Summary().DataPoints().AppendEmpty()
+	dp.SetCount(10)
+	dp.SetSum(100)
+	qv := dp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.5)
+	qv.SetValue(9)
+
+	actMd, actM := newTestMetrics("test.summary", pmetric.MetricTypeSummary)
+	actDp := actM.Summary().DataPoints().AppendEmpty()
+	actDp.SetCount(10)
+	actDp.SetSum(100)
+	actQv := actDp.QuantileValues().AppendEmpty()
+	actQv.SetQuantile(0.5)
+	actQv.SetValue(11)
+
+	err := CompareMetrics(expMd, actMd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quantileValues[0].value")
+}
+
+func TestCompareMetrics_SummaryMatch(t *testing.T) {
+	expMd, expM := newTestMetrics("test.summary", pmetric.MetricTypeSummary)
+	dp := expM.Summary().DataPoints().AppendEmpty()
+	dp.SetCount(10)
+	dp.SetSum(100)
+	qv := dp.QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.5)
+	qv.SetValue(9)
+
+	actMd, actM := newTestMetrics("test.summary", pmetric.MetricTypeSummary)
+	actDp := actM.Summary().DataPoints().AppendEmpty()
+	actDp.SetCount(10)
+	actDp.SetSum(100)
+	actQv := actDp.QuantileValues().AppendEmpty()
+	actQv.SetQuantile(0.5)
+	actQv.SetValue(9)
+
+	assert.NoError(t, CompareMetrics(expMd, actMd))
+}
+
+func TestCompareMetrics_HistogramSortSlices(t *testing.T) {
+	expMd, expM := newTestMetrics("test.histogram", pmetric.MetricTypeHistogram)
+	dp1 := expM.Histogram().DataPoints().AppendEmpty()
+	dp1.Attributes().PutStr("state", "used")
+	dp1.SetCount(1)
+	dp2 := expM.Histogram().DataPoints().AppendEmpty()
+	dp2.Attributes().PutStr("state", "free")
+	dp2.SetCount(2)
+
+	actMd, actM := newTestMetrics("test.histogram", pmetric.MetricTypeHistogram)
+	actDp1 := actM.Histogram().DataPoints().AppendEmpty()
+	actDp1.Attributes().PutStr("state", "free")
+	actDp1.SetCount(2)
+	actDp2 := actM.Histogram().DataPoints().AppendEmpty()
+	actDp2.Attributes().PutStr("state", "used")
+	actDp2.SetCount(1)
+
+	assert.Error(t, CompareMetrics(expMd, actMd))
+	assert.NoError(t, CompareMetrics(expMd, actMd, SortSlices()))
+}
@@ -0,0 +1,114 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+)
+
+// CompareTraces compares expected and actual ptrace.Traces and returns an
+// error describing every mismatch found, path-qualified the same way as
+// CompareMetrics.
+func CompareTraces(expected, actual ptrace.Traces, opts ...Option) error {
+	o := applyOptions(opts)
+
+	expectedRSs := expected.ResourceSpans()
+	actualRSs := actual.ResourceSpans()
+	if o.SortSlices {
+		sortResourceSpans(expectedRSs)
+		sortResourceSpans(actualRSs)
+	}
+
+	if expectedRSs.Len() != actualRSs.Len() {
+		return fmt.Errorf("number of resourceSpans does not match expected: %d, actual: %d", expectedRSs.Len(), actualRSs.Len())
+	}
+
+	var errs error
+	for i := 0; i < expectedRSs.Len(); i++ {
+		errs = multierr.Append(errs, compareResourceSpans(fmt.Sprintf("resourceSpans[%d]", i), expectedRSs.At(i), actualRSs.At(i), o))
+	}
+	return errs
+}
+
+func compareResourceSpans(path string, expected, actual ptrace.ResourceSpans, o Options) error {
+	var errs error
+	errs = multierr.Append(errs, compareAttributes(path+".resource.attributes", expected.Resource().Attributes(), actual.Resource().Attributes(), o))
+
+	expectedSSs := expected.ScopeSpans()
+	actualSSs := actual.ScopeSpans()
+	if expectedSSs.Len() != actualSSs.Len() {
+		return multierr.Append(errs, fmt.Errorf("%s: number of scopeSpans does not match expected: %d, actual: %d", path, expectedSSs.Len(), actualSSs.Len()))
+	}
+
+	for i := 0; i < expectedSSs.Len(); i++ {
+		errs = multierr.Append(errs, compareScopeSpans(fmt.Sprintf("%s.scopeSpans[%d]", path, i), expectedSSs.At(i), actualSSs.At(i), o))
+	}
+	return errs
+}
+
+func compareScopeSpans(path string, expected, actual ptrace.ScopeSpans, o Options) error {
+	expectedSpans := expected.Spans()
+	actualSpans := actual.Spans()
+	if o.SortSlices {
+		sortSpans(expectedSpans)
+		sortSpans(actualSpans)
+	}
+
+	if expectedSpans.Len() != actualSpans.Len() {
+		return fmt.Errorf("%s: number of spans does not match expected: %d, actual: %d", path, expectedSpans.Len(), actualSpans.Len())
+	}
+
+	var errs error
+	for i := 0; i < expectedSpans.Len(); i++ {
+		errs = multierr.Append(errs, compareSpan(fmt.Sprintf("%s.spans[%d]", path, i), expectedSpans.At(i), actualSpans.At(i), o))
+	}
+	return errs
+}
+
+func compareSpan(path string, expected, actual ptrace.Span, o Options) error {
+	var errs error
+	errs = multierr.Append(errs, compareAttributes(path+".attributes", expected.Attributes(), actual.Attributes(), o))
+
+	if expected.Name() != actual.Name() {
+		errs = multierr.Append(errs, fmt.Errorf("%s.name: expected %q, got %q", path, expected.Name(), actual.Name()))
+	}
+	if expected.Kind() != actual.Kind() {
+		errs = multierr.Append(errs, fmt.Errorf("%s.kind: expected %v, got %v", path, expected.Kind(), actual.Kind()))
+	}
+	if !o.IgnoreTimestamps {
+		if expected.StartTimestamp() != actual.StartTimestamp() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.startTimestamp: expected %v, got %v", path, expected.StartTimestamp(), actual.StartTimestamp()))
+		}
+		if expected.EndTimestamp() != actual.EndTimestamp() {
+			errs = multierr.Append(errs, fmt.Errorf("%s.endTimestamp: expected %v, got %v", path, expected.EndTimestamp(), actual.EndTimestamp()))
+		}
+	}
+	return errs
+}
+
+func sortSpans(spans ptrace.SpanSlice) {
+	spans.Sort(func(a, b ptrace.Span) bool {
+		return attributesKey(a.Attributes()) < attributesKey(b.Attributes())
+	})
+}
+
+func sortResourceSpans(rss ptrace.ResourceSpansSlice) {
+	rss.Sort(func(a, b ptrace.ResourceSpans) bool {
+		return attributesKey(a.Resource().Attributes()) < attributesKey(b.Resource().Attributes())
+	})
+}
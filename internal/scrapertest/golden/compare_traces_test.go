@@ -0,0 +1,69 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func newTestSpan(rsAttr, name string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("resource", rsAttr)
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName(name)
+	return td
+}
+
+func TestCompareTraces_NameDiffers(t *testing.T) {
+	expected := newTestSpan("r1", "span-a")
+	actual := newTestSpan("r1", "span-b")
+
+	err := CompareTraces(expected, actual)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name")
+}
+
+func TestCompareTraces_Match(t *testing.T) {
+	expected := newTestSpan("r1", "span-a")
+	actual := newTestSpan("r1", "span-a")
+
+	assert.NoError(t, CompareTraces(expected, actual))
+}
+
+func TestCompareTraces_ResourceOrderSortSlices(t *testing.T) {
+	expected := ptrace.NewTraces()
+	rs1 := expected.ResourceSpans().AppendEmpty()
+	rs1.Resource().Attributes().PutStr("resource", "r1")
+	rs1.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("first")
+	rs2 := expected.ResourceSpans().AppendEmpty()
+	rs2.Resource().Attributes().PutStr("resource", "r2")
+	rs2.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("second")
+
+	actual := ptrace.NewTraces()
+	ars1 := actual.ResourceSpans().AppendEmpty()
+	ars1.Resource().Attributes().PutStr("resource", "r2")
+	ars1.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("second")
+	ars2 := actual.ResourceSpans().AppendEmpty()
+	ars2.Resource().Attributes().PutStr("resource", "r1")
+	ars2.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("first")
+
+	assert.Error(t, CompareTraces(expected, actual))
+	assert.NoError(t, CompareTraces(expected, actual, SortSlices()))
+}
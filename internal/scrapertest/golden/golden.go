@@ -12,38 +12,115 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// nolint:errcheck
+// Package golden provides utilities for reading and writing fixtures used
+// in scraper and receiver tests, plus option-driven comparison helpers
+// (CompareMetrics, CompareLogs, CompareTraces) that produce a structured,
+// path-qualified diff instead of a raw struct dump.
 package golden // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
 
 import (
 	"encoding/json"
-	"io/ioutil"
+	"fmt"
+	"os"
+	"path/filepath"
 
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"gopkg.in/yaml.v3"
 )
 
-// ReadMetrics reads a pmetric.Metrics from the specified file
+// ReadMetrics reads a pmetric.Metrics from the JSON-encoded file at filePath.
 func ReadMetrics(filePath string) (pmetric.Metrics, error) {
-	expectedFileBytes, err := ioutil.ReadFile(filePath)
+	fileBytes, err := os.ReadFile(filePath)
 	if err != nil {
 		return pmetric.Metrics{}, err
 	}
 	unmarshaller := pmetric.NewJSONUnmarshaler()
-	return unmarshaller.UnmarshalMetrics(expectedFileBytes)
+	return unmarshaller.UnmarshalMetrics(fileBytes)
 }
 
-// WriteMetrics writes a pmetric.Metrics to the specified file
+// WriteMetrics writes a pmetric.Metrics to the JSON-encoded file at filePath.
 func WriteMetrics(filePath string, metrics pmetric.Metrics) error {
 	fileBytes, err := pmetric.NewJSONMarshaler().MarshalMetrics(metrics)
 	if err != nil {
 		return err
 	}
 	var jsonVal map[string]interface{}
-	json.Unmarshal(fileBytes, &jsonVal)
+	if err = json.Unmarshal(fileBytes, &jsonVal); err != nil {
+		return err
+	}
 	b, err := json.MarshalIndent(jsonVal, "", "   ")
 	if err != nil {
 		return err
 	}
 	b = append(b, []byte("\n")...)
-	return ioutil.WriteFile(filePath, b, 0600)
+	return writeFileAtomic(filePath, b)
+}
+
+// ReadMetricsYAML reads a pmetric.Metrics from the YAML-encoded file at
+// filePath. YAML fixtures are line-oriented and free of JSON's braces and
+// quoting, which makes them easier to review in a diff.
+func ReadMetricsYAML(filePath string) (pmetric.Metrics, error) {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return pmetric.Metrics{}, err
+	}
+	jsonBytes, err := yamlToJSON(fileBytes)
+	if err != nil {
+		return pmetric.Metrics{}, err
+	}
+	return pmetric.NewJSONUnmarshaler().UnmarshalMetrics(jsonBytes)
+}
+
+// WriteMetricsYAML writes a pmetric.Metrics to the YAML-encoded file at
+// filePath.
+func WriteMetricsYAML(filePath string, metrics pmetric.Metrics) error {
+	fileBytes, err := pmetric.NewJSONMarshaler().MarshalMetrics(metrics)
+	if err != nil {
+		return err
+	}
+	yamlBytes, err := jsonToYAML(fileBytes)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filePath, yamlBytes)
+}
+
+// writeFileAtomic writes b to filePath by first writing to a temporary file
+// in the same directory and renaming it into place, so a crash mid-write
+// cannot leave a truncated fixture behind.
+func writeFileAtomic(filePath string, b []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpPath, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+func yamlToJSON(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("unmarshal YAML fixture: %w", err)
+	}
+	return json.Marshal(v)
+}
+
+func jsonToYAML(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("unmarshal JSON fixture: %w", err)
+	}
+	return yaml.Marshal(v)
 }
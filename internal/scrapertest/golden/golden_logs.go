@@ -0,0 +1,76 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+
+import (
+	"encoding/json"
+	"os"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// ReadLogs reads a plog.Logs from the JSON-encoded file at filePath.
+func ReadLogs(filePath string) (plog.Logs, error) {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	unmarshaller := plog.NewJSONUnmarshaler()
+	return unmarshaller.UnmarshalLogs(fileBytes)
+}
+
+// WriteLogs writes a plog.Logs to the JSON-encoded file at filePath.
+func WriteLogs(filePath string, logs plog.Logs) error {
+	fileBytes, err := plog.NewJSONMarshaler().MarshalLogs(logs)
+	if err != nil {
+		return err
+	}
+	var jsonVal map[string]interface{}
+	if err = json.Unmarshal(fileBytes, &jsonVal); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(jsonVal, "", "   ")
+	if err != nil {
+		return err
+	}
+	b = append(b, []byte("\n")...)
+	return writeFileAtomic(filePath, b)
+}
+
+// ReadLogsYAML reads a plog.Logs from the YAML-encoded file at filePath.
+func ReadLogsYAML(filePath string) (plog.Logs, error) {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	jsonBytes, err := yamlToJSON(fileBytes)
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	return plog.NewJSONUnmarshaler().UnmarshalLogs(jsonBytes)
+}
+
+// WriteLogsYAML writes a plog.Logs to the YAML-encoded file at filePath.
+func WriteLogsYAML(filePath string, logs plog.Logs) error {
+	fileBytes, err := plog.NewJSONMarshaler().MarshalLogs(logs)
+	if err != nil {
+		return err
+	}
+	yamlBytes, err := jsonToYAML(fileBytes)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filePath, yamlBytes)
+}
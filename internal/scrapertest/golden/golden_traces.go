@@ -0,0 +1,78 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+
+import (
+	"encoding/json"
+	"os"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// ReadTraces reads a ptrace.Traces from the JSON-encoded file at filePath.
+func ReadTraces(filePath string) (ptrace.Traces, error) {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	unmarshaller := ptrace.NewJSONUnmarshaler()
+	return unmarshaller.UnmarshalTraces(fileBytes)
+}
+
+// WriteTraces writes a ptrace.Traces to the JSON-encoded file at filePath.
+func WriteTraces(filePath string, traces ptrace.Traces) error {
+	fileBytes, err := ptrace.NewJSONMarshaler().MarshalTraces(traces)
+	if err != nil {
+		return err
+	}
+	var jsonVal map[string]interface{}
+	if err = json.Unmarshal(fileBytes, &jsonVal); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(jsonVal, "", "   ")
+	if err != nil {
+		return err
+	}
+	b = append(b, []byte("\n")...)
+	return writeFileAtomic(filePath, b)
+}
+
+// ReadTracesYAML reads a ptrace.Traces from the YAML-encoded file at
+// filePath.
+func ReadTracesYAML(filePath string) (ptrace.Traces, error) {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	jsonBytes, err := yamlToJSON(fileBytes)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	return ptrace.NewJSONUnmarshaler().UnmarshalTraces(jsonBytes)
+}
+
+// WriteTracesYAML writes a ptrace.Traces to the YAML-encoded file at
+// filePath.
+func WriteTracesYAML(filePath string, traces ptrace.Traces) error {
+	fileBytes, err := ptrace.NewJSONMarshaler().MarshalTraces(traces)
+	if err != nil {
+		return err
+	}
+	yamlBytes, err := jsonToYAML(fileBytes)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filePath, yamlBytes)
+}
@@ -0,0 +1,99 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golden // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+
+import "regexp"
+
+// Options controls how CompareMetrics, CompareLogs, and CompareTraces
+// compare expected and actual pdata. The zero value turns every relaxation
+// below off. Only the fields the Compare functions check are compared;
+// others, such as scope names, data point start timestamps, log observed
+// timestamps and span IDs, are never compared.
+type Options struct {
+	// IgnoreTimestamps skips comparison of the timestamps that are
+	// otherwise compared: the timestamp of gauge and sum data points, the
+	// timestamp of log records, and the start and end timestamps of spans.
+	IgnoreTimestamps bool
+
+	// SortSlices sorts resource slices, metric (or log record/span)
+	// slices, and data points before comparing, so that comparisons are
+	// insensitive to their collection order. Scope slices are not sorted
+	// and are compared in order.
+	SortSlices bool
+
+	// FloatComparisonDelta, when non-zero, allows numeric data point
+	// values to differ by up to this amount without being reported as a
+	// mismatch.
+	FloatComparisonDelta float64
+
+	// MaskAttributes holds compiled patterns for attribute keys whose
+	// values should be ignored during comparison, e.g. to mask
+	// non-deterministic values like generated IDs.
+	MaskAttributes []*regexp.Regexp
+}
+
+// Option applies a single comparison setting to an Options value.
+type Option func(*Options)
+
+// IgnoreTimestamps returns an Option that skips timestamp comparison.
+func IgnoreTimestamps() Option {
+	return func(o *Options) {
+		o.IgnoreTimestamps = true
+	}
+}
+
+// SortSlices returns an Option that sorts comparable slices before
+// comparing them, making the comparison order-insensitive.
+func SortSlices() Option {
+	return func(o *Options) {
+		o.SortSlices = true
+	}
+}
+
+// WithFloatComparisonDelta returns an Option that tolerates up to delta of
+// difference between expected and actual numeric data point values.
+func WithFloatComparisonDelta(delta float64) Option {
+	return func(o *Options) {
+		o.FloatComparisonDelta = delta
+	}
+}
+
+// MaskAttribute returns an Option that ignores the value of any attribute
+// whose key matches the given regular expression pattern during
+// comparison. It panics if pattern does not compile, consistent with
+// regexp.MustCompile's use for fixture helpers invoked only from tests.
+func MaskAttribute(pattern string) Option {
+	re := regexp.MustCompile(pattern)
+	return func(o *Options) {
+		o.MaskAttributes = append(o.MaskAttributes, re)
+	}
+}
+
+func (o Options) maskedAttribute(key string) bool {
+	for _, re := range o.MaskAttributes {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func applyOptions(opts []Option) Options {
+	var o Options
+	for _, apply := range opts {
+		apply(&o)
+	}
+	return o
+}
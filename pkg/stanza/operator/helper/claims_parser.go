@@ -0,0 +1,380 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+)
+
+// jwksFetchTimeout bounds how long a single JWKS refresh may take. Without
+// this, a slow or hung jwks_url stalls every RS256 verification serialized
+// behind jwksCache.mu, turning a JWKS outage into a full operator stall
+// instead of a per-entry error.
+const jwksFetchTimeout = 10 * time.Second
+
+var jwksHTTPClient = &http.Client{Timeout: jwksFetchTimeout}
+
+// wellKnownClaimFields maps the registered JWT claims (RFC 7519 section 4.1)
+// to the entry attribute they are promoted to when `ClaimsParser.Claims`
+// does not explicitly remap them.
+var wellKnownClaimFields = map[string]string{
+	"sub": "jwt.subject",
+	"iss": "jwt.issuer",
+	"aud": "jwt.audience",
+	"exp": "jwt.expiry",
+	"iat": "jwt.issued_at",
+}
+
+// ClaimsParser decodes a JWT bearer token found in an entry and promotes
+// selected claims onto the entry as attributes. Signature verification is
+// optional: when JWKSURL and HMACSecret are both unset, tokens are decoded
+// without signature verification so that unsigned debugging/log-only
+// tokens can still be enriched. Issuer, Audience and expiry checks apply
+// either way.
+type ClaimsParser struct {
+	// ParseFrom is where the raw JWT (optionally prefixed with "Bearer ")
+	// is read from, e.g. the Authorization header already promoted onto
+	// the entry by an earlier operator.
+	ParseFrom entry.Field `mapstructure:"parse_from"                json:"parse_from"                yaml:"parse_from"`
+
+	// Issuer, if set, is compared against the token's `iss` claim; tokens
+	// with a mismatching issuer are rejected.
+	Issuer string `mapstructure:"issuer,omitempty"          json:"issuer,omitempty"          yaml:"issuer,omitempty"`
+
+	// Audience, if set, is compared against the token's `aud` claim;
+	// tokens that do not list this audience are rejected.
+	Audience string `mapstructure:"audience,omitempty"        json:"audience,omitempty"        yaml:"audience,omitempty"`
+
+	// JWKSURL, if set, enables RS256 signature verification: keys are
+	// fetched from this endpoint and cached for JWKSRefreshInterval.
+	JWKSURL string `mapstructure:"jwks_url,omitempty"        json:"jwks_url,omitempty"        yaml:"jwks_url,omitempty"`
+
+	// JWKSRefreshInterval controls how often the JWKS key set is
+	// refetched. Defaults to 1 hour.
+	JWKSRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval,omitempty" json:"jwks_refresh_interval,omitempty" yaml:"jwks_refresh_interval,omitempty"`
+
+	// HMACSecret, if set, enables HS256 signature verification.
+	HMACSecret string `mapstructure:"hmac_secret,omitempty"     json:"hmac_secret,omitempty"     yaml:"hmac_secret,omitempty"`
+
+	// SkipExpiry allows tokens whose `exp` claim is in the past to still
+	// be parsed, rather than rejected.
+	SkipExpiry bool `mapstructure:"skip_expiry,omitempty"     json:"skip_expiry,omitempty"     yaml:"skip_expiry,omitempty"`
+
+	// Claims maps claim name to the entry.Field it should be written to.
+	// Claims not listed here fall back to wellKnownClaimFields and are
+	// otherwise left unset on the entry.
+	Claims map[string]entry.Field `mapstructure:"claims,omitempty"          json:"claims,omitempty"          yaml:"claims,omitempty"`
+
+	keySet *jwksCache
+}
+
+// Validate validates a ClaimsParser, filling in the default ParseFrom and
+// JWKSRefreshInterval and building its JWKS cache.
+func (p *ClaimsParser) Validate() error {
+	if p.ParseFrom == (entry.Field{}) {
+		p.ParseFrom = entry.NewAttributeField("Authorization")
+	}
+	if p.JWKSRefreshInterval <= 0 {
+		p.JWKSRefreshInterval = time.Hour
+	}
+	p.keySet = newJWKSCache(p.JWKSURL, p.JWKSRefreshInterval)
+	return nil
+}
+
+// Parse will parse a JWT found on the entry and promote its claims.
+func (p *ClaimsParser) Parse(ent *entry.Entry) error {
+	value, ok := ent.Get(p.ParseFrom)
+	if !ok {
+		return fmt.Errorf("entry is missing the claims parser parse_from field %s", p.ParseFrom)
+	}
+	raw, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("type %T cannot be parsed as a JWT", value)
+	}
+	raw = strings.TrimPrefix(raw, "Bearer ")
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid JWT: expected 3 dot-separated parts, got %d", len(parts))
+	}
+
+	header, err := decodeSegment(parts[0])
+	if err != nil {
+		return fmt.Errorf("decode JWT header: %w", err)
+	}
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return fmt.Errorf("decode JWT payload: %w", err)
+	}
+
+	var head jwtHeader
+	if err := json.Unmarshal(header, &head); err != nil {
+		return fmt.Errorf("unmarshal JWT header: %w", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return fmt.Errorf("unmarshal JWT claims: %w", err)
+	}
+
+	if p.verificationConfigured() {
+		if err := p.verify(head, parts[0]+"."+parts[1], parts[2]); err != nil {
+			return fmt.Errorf("verify JWT signature: %w", err)
+		}
+	}
+
+	if err := p.validateClaims(claims); err != nil {
+		return err
+	}
+
+	for claim, value := range claims {
+		field, ok := p.Claims[claim]
+		if !ok {
+			attr, known := wellKnownClaimFields[claim]
+			if !known {
+				continue
+			}
+			field = entry.NewAttributeField(attr)
+		}
+		if err := ent.Set(field, value); err != nil {
+			return fmt.Errorf("set claim %q: %w", claim, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *ClaimsParser) verificationConfigured() bool {
+	return p.JWKSURL != "" || p.HMACSecret != ""
+}
+
+// validateClaims enforces issuer, audience, and expiry checks.
+func (p *ClaimsParser) validateClaims(claims map[string]interface{}) error {
+	if p.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != p.Issuer {
+			return fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+
+	if p.Audience != "" && !claimsContainAudience(claims["aud"], p.Audience) {
+		return fmt.Errorf("token is not intended for audience %q", p.Audience)
+	}
+
+	if !p.SkipExpiry {
+		if exp, ok := claims["exp"].(float64); ok && time.Unix(int64(exp), 0).Before(time.Now()) {
+			return fmt.Errorf("token expired at %s", time.Unix(int64(exp), 0))
+		}
+	}
+
+	return nil
+}
+
+func claimsContainAudience(aud interface{}, want string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == want
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verify validates the JWT signature according to the header's declared
+// algorithm. HS256 is verified against HMACSecret; RS256 is verified
+// against a key fetched (and cached) from JWKSURL.
+func (p *ClaimsParser) verify(head jwtHeader, signingInput, signature string) error {
+	sig, err := decodeSegment(signature)
+	if err != nil {
+		return fmt.Errorf("decode JWT signature: %w", err)
+	}
+
+	switch head.Algorithm {
+	case "HS256":
+		if p.HMACSecret == "" {
+			return fmt.Errorf("received HS256 token but no hmac_secret is configured")
+		}
+		mac := hmac.New(sha256.New, []byte(p.HMACSecret))
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return fmt.Errorf("HMAC signature mismatch")
+		}
+		return nil
+	case "RS256":
+		if p.JWKSURL == "" {
+			return fmt.Errorf("received RS256 token but no jwks_url is configured")
+		}
+		key, err := p.keySet.key(head.KeyID)
+		if err != nil {
+			return err
+		}
+		hashed := sha256.Sum256([]byte(signingInput))
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig)
+	default:
+		return fmt.Errorf("unsupported JWT signing algorithm %q", head.Algorithm)
+	}
+}
+
+// jwtHeader is the decoded JOSE header of a JWT.
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+func decodeSegment(seg string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(seg)
+}
+
+// jwk is a single entry of a JSON Web Key Set (RFC 7517), restricted to the
+// RSA fields this parser needs to verify RS256 signatures.
+type jwk struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// parseRSAPublicKey builds an *rsa.PublicKey from a JWK's base64url-encoded
+// modulus and exponent.
+func parseRSAPublicKey(k jwk) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+
+	e := 0
+	for _, b := range eBytes {
+		e = e<<8 | int(b)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: e,
+	}, nil
+}
+
+// jwksCache fetches and caches RS256 public keys by key ID, refreshing the
+// key set at most once per refreshInterval.
+type jwksCache struct {
+	url             string
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	lastRefresh time.Time
+	fetch       func(url string) (map[string]*rsa.PublicKey, error)
+}
+
+func newJWKSCache(url string, refreshInterval time.Duration) *jwksCache {
+	return &jwksCache{
+		url:             url,
+		refreshInterval: refreshInterval,
+		fetch:           fetchJWKS,
+	}
+}
+
+func (c *jwksCache) key(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil || time.Since(c.lastRefresh) > c.refreshInterval {
+		keys, err := c.fetch(c.url)
+		if err != nil {
+			if c.keys != nil {
+				// Serve stale keys rather than fail outright on a
+				// transient refresh error.
+				return c.lookup(kid)
+			}
+			return nil, err
+		}
+		c.keys = keys
+		c.lastRefresh = time.Now()
+	}
+
+	return c.lookup(kid)
+}
+
+func (c *jwksCache) lookup(kid string) (*rsa.PublicKey, error) {
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+// fetchJWKS retrieves and parses the JWK set served at url, bounded by
+// jwksFetchTimeout so a slow endpoint cannot stall verification.
+func fetchJWKS(url string) (map[string]*rsa.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil) //nolint:gosec // url is operator config, not user input
+	if err != nil {
+		return nil, fmt.Errorf("build JWKS request: %w", err)
+	}
+
+	resp, err := jwksHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch JWKS: unexpected status %s", resp.Status)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.KeyType != "RSA" {
+			continue
+		}
+		pub, err := parseRSAPublicKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("parse key %q: %w", k.KeyID, err)
+		}
+		keys[k.KeyID] = pub
+	}
+	return keys, nil
+}
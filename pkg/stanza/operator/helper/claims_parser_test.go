@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func unsignedJWT(t *testing.T, claims map[string]interface{}) string {
+	header := encodeSegment(t, map[string]string{"alg": "none"})
+	payload := encodeSegment(t, claims)
+	return header + "." + payload + "."
+}
+
+func hs256JWT(t *testing.T, claims map[string]interface{}, secret string) string {
+	header := encodeSegment(t, map[string]string{"alg": "HS256"})
+	payload := encodeSegment(t, claims)
+	signingInput := header + "." + payload
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	return signingInput + "." + sig
+}
+
+func TestClaimsParser_ValidateDefaultsParseFrom(t *testing.T) {
+	p := &ClaimsParser{}
+	require.NoError(t, p.Validate())
+	assert.Equal(t, entry.NewAttributeField("Authorization"), p.ParseFrom)
+}
+
+func TestClaimsParser_ValidateKeepsExplicitParseFrom(t *testing.T) {
+	p := &ClaimsParser{ParseFrom: entry.NewBodyField()}
+	require.NoError(t, p.Validate())
+	assert.Equal(t, entry.NewBodyField(), p.ParseFrom)
+}
+
+func TestClaimsParser_ParseUnverified(t *testing.T) {
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization")}
+	require.NoError(t, p.Validate())
+
+	claims := map[string]interface{}{
+		"sub":    "user-1",
+		"iss":    "issuer-1",
+		"custom": "value",
+	}
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), "Bearer "+unsignedJWT(t, claims)))
+
+	require.NoError(t, p.Parse(ent))
+
+	v, ok := ent.Get(entry.NewAttributeField("jwt.subject"))
+	require.True(t, ok)
+	assert.Equal(t, "user-1", v)
+
+	v, ok = ent.Get(entry.NewAttributeField("jwt.issuer"))
+	require.True(t, ok)
+	assert.Equal(t, "issuer-1", v)
+}
+
+func TestClaimsParser_ParseRemapsClaimToConfiguredField(t *testing.T) {
+	p := &ClaimsParser{
+		ParseFrom: entry.NewAttributeField("Authorization"),
+		Claims: map[string]entry.Field{
+			"custom": entry.NewAttributeField("my_custom_claim"),
+		},
+	}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), unsignedJWT(t, map[string]interface{}{"custom": "value"})))
+
+	require.NoError(t, p.Parse(ent))
+
+	v, ok := ent.Get(entry.NewAttributeField("my_custom_claim"))
+	require.True(t, ok)
+	assert.Equal(t, "value", v)
+}
+
+func TestClaimsParser_ParseRejectsExpiredToken(t *testing.T) {
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization")}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), unsignedJWT(t, map[string]interface{}{
+		"exp": float64(time.Now().Add(-time.Hour).Unix()),
+	})))
+
+	err := p.Parse(ent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+}
+
+func TestClaimsParser_ParseSkipExpiry(t *testing.T) {
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization"), SkipExpiry: true}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), unsignedJWT(t, map[string]interface{}{
+		"exp": float64(time.Now().Add(-time.Hour).Unix()),
+	})))
+
+	assert.NoError(t, p.Parse(ent))
+}
+
+func TestClaimsParser_ParseHS256(t *testing.T) {
+	const secret = "super-secret"
+
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization"), HMACSecret: secret}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), hs256JWT(t, map[string]interface{}{"sub": "user-1"}, secret)))
+
+	require.NoError(t, p.Parse(ent))
+
+	v, ok := ent.Get(entry.NewAttributeField("jwt.subject"))
+	require.True(t, ok)
+	assert.Equal(t, "user-1", v)
+}
+
+func TestClaimsParser_ParseHS256BadSignature(t *testing.T) {
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization"), HMACSecret: "super-secret"}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), hs256JWT(t, map[string]interface{}{"sub": "user-1"}, "wrong-secret")))
+
+	err := p.Parse(ent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signature")
+}
+
+func TestClaimsParser_ParseRejectsWrongIssuer(t *testing.T) {
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization"), Issuer: "expected-issuer"}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), unsignedJWT(t, map[string]interface{}{"iss": "other-issuer"})))
+
+	err := p.Parse(ent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "issuer")
+}
+
+func base64URLUint(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func rs256JWT(t *testing.T, claims map[string]interface{}, key *rsa.PrivateKey, kid string) string {
+	header := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid})
+	payload := encodeSegment(t, claims)
+	signingInput := header + "." + payload
+	hashed := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func jwksServer(t *testing.T, kid string, pub *rsa.PublicKey) *httptest.Server {
+	body, err := json.Marshal(jwkSet{Keys: []jwk{{
+		KeyID:   kid,
+		KeyType: "RSA",
+		N:       base64URLUint(pub.N),
+		E:       base64URLUint(big.NewInt(int64(pub.E))),
+	}}})
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+}
+
+func TestClaimsParser_ParseRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := jwksServer(t, "key-1", &key.PublicKey)
+	defer server.Close()
+
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization"), JWKSURL: server.URL}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), rs256JWT(t, map[string]interface{}{"sub": "user-1"}, key, "key-1")))
+
+	require.NoError(t, p.Parse(ent))
+
+	v, ok := ent.Get(entry.NewAttributeField("jwt.subject"))
+	require.True(t, ok)
+	assert.Equal(t, "user-1", v)
+}
+
+func TestClaimsParser_ParseRS256UnknownKeyID(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := jwksServer(t, "key-1", &key.PublicKey)
+	defer server.Close()
+
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization"), JWKSURL: server.URL}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), rs256JWT(t, map[string]interface{}{"sub": "user-1"}, key, "key-2")))
+
+	err = p.Parse(ent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no key found")
+}
+
+func TestClaimsParser_ParseRS256BadSignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := jwksServer(t, "key-1", &key.PublicKey)
+	defer server.Close()
+
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization"), JWKSURL: server.URL}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), rs256JWT(t, map[string]interface{}{"sub": "user-1"}, otherKey, "key-1")))
+
+	err = p.Parse(ent)
+	require.Error(t, err)
+}
+
+func TestJWKSCache_FetchRespectsTimeout(t *testing.T) {
+	blocked := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-blocked
+	}))
+	defer server.Close()
+	defer close(blocked)
+
+	original := jwksHTTPClient.Timeout
+	jwksHTTPClient.Timeout = 50 * time.Millisecond
+	defer func() { jwksHTTPClient.Timeout = original }()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := fetchJWKS(server.URL)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("fetchJWKS did not respect the configured timeout")
+	}
+}
+
+func TestClaimsParser_ParseMalformedToken(t *testing.T) {
+	p := &ClaimsParser{ParseFrom: entry.NewAttributeField("Authorization")}
+	require.NoError(t, p.Validate())
+
+	ent := entry.New()
+	require.NoError(t, ent.Set(entry.NewAttributeField("Authorization"), "not-a-jwt"))
+
+	err := p.Parse(ent)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "invalid JWT"))
+}
@@ -42,6 +42,7 @@ type ParserConfig struct {
 	Config          *Config          `mapstructure:"severity,omitempty"  json:"severity,omitempty"  yaml:"severity,omitempty"`
 	TraceParser     *TraceParser     `mapstructure:"trace,omitempty"     json:"trace,omitempty"     yaml:"trace,omitempty"`
 	ScopeNameParser *ScopeNameParser `mapstructure:"scope_name,omitempty"     json:"scope_name,omitempty"     yaml:"scope_name,omitempty"`
+	ClaimsParser    *ClaimsParser    `mapstructure:"claims,omitempty"     json:"claims,omitempty"     yaml:"claims,omitempty"`
 }
 
 // Build will build a parser operator.
@@ -83,6 +84,13 @@ func (c ParserConfig) Build(logger *zap.SugaredLogger) (ParserOperator, error) {
 		parserOperator.ScopeNameParser = c.ScopeNameParser
 	}
 
+	if c.ClaimsParser != nil {
+		if err := c.ClaimsParser.Validate(); err != nil {
+			return ParserOperator{}, err
+		}
+		parserOperator.ClaimsParser = c.ClaimsParser
+	}
+
 	return parserOperator, nil
 }
 
@@ -95,6 +103,7 @@ type ParserOperator struct {
 	SeverityParser  *SeverityParser
 	TraceParser     *TraceParser
 	ScopeNameParser *ScopeNameParser
+	ClaimsParser    *ClaimsParser
 }
 
 // ProcessWith will run ParseWith on the entry, then forward the entry on to the next operators.
@@ -168,6 +177,11 @@ func (p *ParserOperator) ParseWith(ctx context.Context, entry *entry.Entry, pars
 		scopeNameParserErr = p.ScopeNameParser.Parse(entry)
 	}
 
+	var claimsParseErr error
+	if p.ClaimsParser != nil {
+		claimsParseErr = p.ClaimsParser.Parse(entry)
+	}
+
 	// Handle time or severity parsing errors after attempting to parse both
 	if timeParseErr != nil {
 		return p.HandleEntryError(ctx, entry, errors.Wrap(timeParseErr, "time parser"))
@@ -181,6 +195,9 @@ func (p *ParserOperator) ParseWith(ctx context.Context, entry *entry.Entry, pars
 	if scopeNameParserErr != nil {
 		return p.HandleEntryError(ctx, entry, errors.Wrap(scopeNameParserErr, "scope_name parser"))
 	}
+	if claimsParseErr != nil {
+		return p.HandleEntryError(ctx, entry, errors.Wrap(claimsParseErr, "claims parser"))
+	}
 	return nil
 }
 
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memoryscraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/memoryscraper"
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/memoryscraper/internal/metadata"
+)
+
+// defaultQuantileWindowSize is used when quantiles are configured without an
+// explicit window_size.
+const defaultQuantileWindowSize = 60
+
+// Config relating to Memory Metric Scraper.
+type Config struct {
+	// Metrics allows customizing scraped metrics representation.
+	Metrics metadata.MetricsSettings `mapstructure:"metrics"`
+
+	// Quantiles is the set of quantiles (in the range (0, 1)), e.g.
+	// []float64{0.5, 0.95, 0.99}, to estimate from a rolling window of
+	// system.memory.utilization samples and emit as data points of the
+	// system.memory.utilization.quantile gauge, one per state and quantile,
+	// carrying a quantile attribute. Leave empty (the default) to skip
+	// estimation entirely.
+	//
+	// Quantiles are only tracked for the "used" and "free" states, not
+	// every state system.memory.utilization reports (e.g. buffered,
+	// cached, slab_reclaimable/unreclaimable on Linux, or inactive on
+	// Darwin/BSD): those are platform-specific, and used/free are the only
+	// two states every supported platform has.
+	Quantiles []float64 `mapstructure:"quantiles"`
+
+	// WindowSize bounds the number of historical utilization samples kept
+	// per state for quantile estimation. Only used when Quantiles is
+	// non-empty. Defaults to 60 samples.
+	WindowSize int `mapstructure:"window_size"`
+}
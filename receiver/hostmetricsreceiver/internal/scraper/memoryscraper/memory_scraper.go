@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memoryscraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/memoryscraper"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/memoryscraper/internal/metadata"
+)
+
+const (
+	memoryUsageMetricsLen       = 1
+	memoryUtilizationMetricsLen = 1
+	metricsLen                  = memoryUsageMetricsLen + memoryUtilizationMetricsLen
+)
+
+// ErrInvalidTotalMem is returned when the total system memory reported is
+// zero, which makes utilization (and, by extension, any quantile derived
+// from it) impossible to compute.
+var ErrInvalidTotalMem = errors.New("invalid total memory reported: 0")
+
+// scraper for Memory Metrics
+type scraper struct {
+	settings component.ReceiverCreateSettings
+	config   *Config
+	mb       *metadata.MetricsBuilder
+
+	// quantileWindows holds one rolling quantile estimator per memory
+	// state returned by memoryUtilizationStateValues ("used" and "free"
+	// only; "cached" and other states are not tracked), each built on its
+	// first sample. Left nil when config.Quantiles is empty.
+	quantileWindows map[string]*quantileWindow
+
+	// for mocking gopsutil mem.VirtualMemory
+	virtualMemory func() (*mem.VirtualMemoryStat, error)
+	bootTime      func() (uint64, error)
+}
+
+// newMemoryScraper creates a Memory Scraper
+func newMemoryScraper(_ context.Context, settings component.ReceiverCreateSettings, cfg *Config) *scraper {
+	return &scraper{settings: settings, config: cfg, virtualMemory: mem.VirtualMemory, bootTime: host.BootTime}
+}
+
+func (s *scraper) start(_ context.Context, _ component.Host) error {
+	// bootTime is queried on start purely to surface host connectivity
+	// issues early; memory scraping itself has no use for the value.
+	if _, err := s.bootTime(); err != nil {
+		return err
+	}
+
+	s.mb = metadata.NewMetricsBuilder(s.config.Metrics, s.settings.BuildInfo)
+	if len(s.config.Quantiles) > 0 {
+		s.quantileWindows = make(map[string]*quantileWindow)
+	}
+	return nil
+}
+
+func (s *scraper) scrape(_ context.Context) (pmetric.Metrics, error) {
+	now := pcommon.NewTimestampFromTime(time.Now())
+	memInfo, err := s.virtualMemory()
+	if err != nil {
+		return pmetric.Metrics{}, scrapererror.NewPartialScrapeError(err, metricsLen)
+	}
+
+	if s.config.Metrics.SystemMemoryUsage.Enabled {
+		s.recordMemoryUsageMetric(now, memInfo)
+	}
+
+	var scrapeErr error
+	if s.config.Metrics.SystemMemoryUtilization.Enabled {
+		scrapeErr = s.recordMemoryUtilizationMetric(now, memInfo)
+	}
+
+	if len(s.config.Quantiles) > 0 && scrapeErr == nil {
+		s.recordMemoryUtilizationQuantileMetrics(now, memInfo)
+	}
+
+	if scrapeErr != nil {
+		return s.mb.Emit(), scrapererror.NewPartialScrapeError(scrapeErr, memoryUtilizationMetricsLen)
+	}
+	return s.mb.Emit(), nil
+}
+
+// recordMemoryUtilizationQuantileMetrics inserts the current per-state
+// utilization ratios into their rolling quantile estimators and, for any
+// estimator holding at least one sample, emits a gauge data point per
+// configured quantile with a `quantile` attribute.
+func (s *scraper) recordMemoryUtilizationQuantileMetrics(now pcommon.Timestamp, memInfo *mem.VirtualMemoryStat) {
+	if memInfo.Total == 0 {
+		return
+	}
+
+	for state, used := range memoryUtilizationStateValues(memInfo) {
+		ratio := float64(used) / float64(memInfo.Total)
+
+		window, ok := s.quantileWindows[state]
+		if !ok {
+			window = newQuantileWindow(s.config.Quantiles, s.config.WindowSize)
+			s.quantileWindows[state] = window
+		}
+		window.Insert(ratio)
+
+		for _, q := range s.config.Quantiles {
+			v := window.Query(q)
+			if v != v { // NaN: no samples recorded yet, skip emit
+				continue
+			}
+			s.mb.RecordSystemMemoryUtilizationQuantileDataPoint(now, v, state, q)
+		}
+	}
+}
+
+// memoryUtilizationStateValues returns the memory states, in bytes, that
+// gopsutil reports consistently across every supported platform. Other
+// state labels (e.g. buffered, cached, slab) are platform-specific and are
+// already covered by the non-quantile system.memory.utilization metric;
+// quantiles are only tracked for the states every platform has. See the
+// Quantiles doc comment on Config for the user-facing version of this.
+func memoryUtilizationStateValues(memInfo *mem.VirtualMemoryStat) map[string]uint64 {
+	return map[string]uint64{
+		metadata.AttributeStateUsed.String(): memInfo.Used,
+		metadata.AttributeStateFree.String(): memInfo.Free,
+	}
+}
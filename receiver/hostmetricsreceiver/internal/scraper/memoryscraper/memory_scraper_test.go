@@ -17,6 +17,7 @@ package memoryscraper
 import (
 	"context"
 	"errors"
+	"math"
 	"runtime"
 	"testing"
 
@@ -190,6 +191,63 @@ func TestScrape_MemoryUtilization(t *testing.T) {
 	}
 }
 
+func TestScrape_Quantiles(t *testing.T) {
+	scraperConfig := Config{
+		Metrics: metadata.MetricsSettings{
+			SystemMemoryUtilization: metadata.MetricSettings{
+				Enabled: true,
+			},
+		},
+		Quantiles:  []float64{0.5, 0.99},
+		WindowSize: 5,
+	}
+	scraper := newMemoryScraper(context.Background(), componenttest.NewNopReceiverCreateSettings(), &scraperConfig)
+
+	err := scraper.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err, "Failed to initialize memory scraper: %v", err)
+
+	// The first scrape only has a single sample in each state's window, so
+	// every configured quantile should already be defined (not NaN).
+	_, err = scraper.scrape(context.Background())
+	require.NoError(t, err, "Failed to scrape metrics: %v", err)
+
+	usedWindow, ok := scraper.quantileWindows[metadata.AttributeStateUsed.String()]
+	require.True(t, ok, "expected a quantile window to have been created for the used state")
+	for _, q := range scraperConfig.Quantiles {
+		assert.False(t, math.IsNaN(usedWindow.Query(q)), "quantile %v should not be NaN after one sample", q)
+	}
+
+	// A second scrape inserts a second sample into the same windows; the
+	// windows should still report non-NaN quantiles for every state.
+	_, err = scraper.scrape(context.Background())
+	require.NoError(t, err, "Failed to scrape metrics: %v", err)
+
+	for state, window := range scraper.quantileWindows {
+		for _, q := range scraperConfig.Quantiles {
+			assert.Falsef(t, math.IsNaN(window.Query(q)), "quantile %v for state %q should not be NaN after two samples", q, state)
+		}
+	}
+}
+
+func TestScrape_QuantilesSkippedWhenUnconfigured(t *testing.T) {
+	scraperConfig := Config{
+		Metrics: metadata.MetricsSettings{
+			SystemMemoryUtilization: metadata.MetricSettings{
+				Enabled: true,
+			},
+		},
+	}
+	scraper := newMemoryScraper(context.Background(), componenttest.NewNopReceiverCreateSettings(), &scraperConfig)
+
+	err := scraper.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err, "Failed to initialize memory scraper: %v", err)
+
+	_, err = scraper.scrape(context.Background())
+	require.NoError(t, err, "Failed to scrape metrics: %v", err)
+
+	assert.Nil(t, scraper.quantileWindows)
+}
+
 func assertMemoryUsageMetricValid(t *testing.T, metric pmetric.Metric, expectedName string) {
 	assert.Equal(t, expectedName, metric.Name())
 	assert.GreaterOrEqual(t, metric.Sum().DataPoints().Len(), 2)
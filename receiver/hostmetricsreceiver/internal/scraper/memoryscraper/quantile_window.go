@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memoryscraper // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver/internal/scraper/memoryscraper"
+
+import (
+	"math"
+
+	"github.com/beorn7/perks/quantile"
+)
+
+// quantileEpsilon is the target error bound passed to the underlying
+// Cormode-Khanna biased-quantile estimator for every configured quantile.
+// This mirrors the epsilon used by Prometheus client summaries, which keeps
+// the sketch small regardless of WindowSize.
+const quantileEpsilon = 0.01
+
+// quantileWindow estimates a fixed set of quantiles over the last
+// windowSize samples using a targeted (biased) quantile estimator
+// (github.com/beorn7/perks/quantile, an implementation of the Cormode-Khanna
+// streaming algorithm). Samples older than the window are dropped by
+// rebuilding the estimator from a ring buffer on every Insert, since the
+// estimator itself has no notion of eviction.
+type quantileWindow struct {
+	targets    map[float64]float64
+	windowSize int
+	samples    []float64
+	next       int
+	count      int
+	stream     *quantile.Stream
+}
+
+// newQuantileWindow creates a quantileWindow that estimates the given
+// quantiles (each in (0, 1)) over the last windowSize samples.
+func newQuantileWindow(quantiles []float64, windowSize int) *quantileWindow {
+	if windowSize <= 0 {
+		windowSize = defaultQuantileWindowSize
+	}
+	targets := make(map[float64]float64, len(quantiles))
+	for _, q := range quantiles {
+		targets[q] = quantileEpsilon
+	}
+	return &quantileWindow{
+		targets:    targets,
+		windowSize: windowSize,
+		samples:    make([]float64, windowSize),
+	}
+}
+
+// Insert records a new sample, evicting the oldest sample once the window
+// has filled, and rebuilds the underlying estimator over the retained
+// window.
+//
+// This rebuilds the whole quantile.Stream on every call rather than
+// inserting into one long-lived stream: quantile.Stream has no operation to
+// remove a sample, so once the window is full there is no way to evict the
+// oldest one without recomputing from the retained samples. That makes
+// Insert O(windowSize) instead of the amortized O(log windowSize) a
+// streaming estimator without eviction would give you. WindowSize is
+// expected to stay small (tens to low hundreds of samples, one scrape
+// interval's worth), so this is a deliberate simplicity-over-throughput
+// tradeoff rather than an oversight.
+func (w *quantileWindow) Insert(v float64) {
+	w.samples[w.next] = v
+	w.next = (w.next + 1) % w.windowSize
+	if w.count < w.windowSize {
+		w.count++
+	}
+
+	stream := quantile.NewTargeted(w.targets)
+	start := 0
+	if w.count == w.windowSize {
+		start = w.next
+	}
+	for i := 0; i < w.count; i++ {
+		stream.Insert(w.samples[(start+i)%w.windowSize])
+	}
+	w.stream = stream
+}
+
+// Query returns the estimated value at quantile q, or NaN if no samples
+// have been recorded yet.
+func (w *quantileWindow) Query(q float64) float64 {
+	if w.stream == nil || w.count == 0 {
+		return math.NaN()
+	}
+	return w.stream.Query(q)
+}
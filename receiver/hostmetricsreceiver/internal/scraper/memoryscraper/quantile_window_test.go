@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memoryscraper
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantileWindow_EmptyReturnsNaN(t *testing.T) {
+	w := newQuantileWindow([]float64{0.5, 0.99}, 10)
+
+	assert.True(t, math.IsNaN(w.Query(0.5)))
+	assert.True(t, math.IsNaN(w.Query(0.99)))
+}
+
+func TestQuantileWindow_SingleSampleReturnsSample(t *testing.T) {
+	w := newQuantileWindow([]float64{0.5, 0.95, 0.99}, 10)
+
+	w.Insert(0.42)
+
+	assert.Equal(t, 0.42, w.Query(0.5))
+	assert.Equal(t, 0.42, w.Query(0.95))
+	assert.Equal(t, 0.42, w.Query(0.99))
+}
+
+func TestQuantileWindow_EvictsOldestOnceWindowFull(t *testing.T) {
+	w := newQuantileWindow([]float64{0.5}, 3)
+
+	for _, v := range []float64{0.1, 0.2, 0.3} {
+		w.Insert(v)
+	}
+	// Window is now full at [0.1, 0.2, 0.3]; the median should be 0.2.
+	assert.InDelta(t, 0.2, w.Query(0.5), quantileEpsilon*3)
+
+	// Evict 0.1 by inserting 0.9: window becomes [0.2, 0.3, 0.9].
+	w.Insert(0.9)
+	assert.InDelta(t, 0.3, w.Query(0.5), quantileEpsilon*3)
+}
+
+func TestQuantileWindow_DefaultsWindowSize(t *testing.T) {
+	w := newQuantileWindow([]float64{0.5}, 0)
+
+	assert.Equal(t, defaultQuantileWindowSize, w.windowSize)
+}